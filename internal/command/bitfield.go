@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/OpenAtomFoundation/pikiwidb/internal/notify"
+	"github.com/OpenAtomFoundation/pikiwidb/internal/store"
+)
+
+// BitFieldOp is a single GET/SET/INCRBY sub-operation within a BITFIELD (or
+// BITFIELD_RO) call, together with the OVERFLOW mode in effect for it.
+type BitFieldOp struct {
+	Kind     string // "GET", "SET" or "INCRBY"
+	Signed   bool
+	Width    uint
+	Offset   uint64
+	Value    int64  // operand for SET/INCRBY, unused for GET
+	Overflow string // "WRAP", "SAT" or "FAIL"
+}
+
+// ParseBitFieldArgs parses the sub-operation tokens following BITFIELD's
+// key argument. When readOnly is set (BITFIELD_RO), any SET or INCRBY
+// sub-operation is rejected, matching Redis's read-only variant.
+func ParseBitFieldArgs(args []string, readOnly bool) ([]BitFieldOp, error) {
+	var ops []BitFieldOp
+	overflow := "WRAP"
+
+	for i := 0; i < len(args); {
+		switch strings.ToUpper(args[i]) {
+		case "OVERFLOW":
+			if i+1 >= len(args) {
+				return nil, ErrSyntax
+			}
+			mode := strings.ToUpper(args[i+1])
+			if mode != "WRAP" && mode != "SAT" && mode != "FAIL" {
+				return nil, ErrSyntax
+			}
+			overflow = mode
+			i += 2
+
+		case "GET":
+			if i+2 >= len(args) {
+				return nil, ErrSyntax
+			}
+			signed, width, err := parseBitType(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			offset, err := parseBitOffset(args[i+2], width)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, BitFieldOp{Kind: "GET", Signed: signed, Width: width, Offset: offset})
+			i += 3
+
+		case "SET", "INCRBY":
+			kind := strings.ToUpper(args[i])
+			if readOnly {
+				return nil, errors.New("ERR BITFIELD_RO only supports the GET subcommand")
+			}
+			if i+3 >= len(args) {
+				return nil, ErrSyntax
+			}
+			signed, width, err := parseBitType(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			offset, err := parseBitOffset(args[i+2], width)
+			if err != nil {
+				return nil, err
+			}
+			value, err := strconv.ParseInt(args[i+3], 10, 64)
+			if err != nil {
+				return nil, ErrSyntax
+			}
+			ops = append(ops, BitFieldOp{Kind: kind, Signed: signed, Width: width, Offset: offset, Value: value, Overflow: overflow})
+			i += 4
+
+		default:
+			return nil, ErrSyntax
+		}
+	}
+	return ops, nil
+}
+
+// parseBitType parses a subfield type token like "u8" or "i16". Unsigned
+// widths top out at 63 bits and signed at 64, same as Redis.
+func parseBitType(s string) (signed bool, width uint, err error) {
+	if len(s) < 2 {
+		return false, 0, ErrSyntax
+	}
+	switch s[0] {
+	case 'i':
+		signed = true
+	case 'u':
+		signed = false
+	default:
+		return false, 0, ErrSyntax
+	}
+	w, err := strconv.Atoi(s[1:])
+	if err != nil || w < 1 || (signed && w > 64) || (!signed && w > 63) {
+		return false, 0, fmt.Errorf("ERR Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is")
+	}
+	return signed, uint(w), nil
+}
+
+// parseBitOffset parses a subfield offset, supporting both the plain bit
+// offset form and the "#N" form (N * width).
+func parseBitOffset(s string, width uint) (uint64, error) {
+	if strings.HasPrefix(s, "#") {
+		n, err := strconv.ParseUint(s[1:], 10, 64)
+		if err != nil {
+			return 0, ErrSyntax
+		}
+		return n * uint64(width), nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, ErrSyntax
+	}
+	return n, nil
+}
+
+// BitField runs a BITFIELD/BITFIELD_RO call against the bitmap stored at
+// key, applying each op in order and returning one reply per op. SET
+// replies with the field's prior value; an INCRBY that fails under
+// OVERFLOW FAIL replies nil for that op but leaves earlier ops in the same
+// call applied. If any op actually wrote to the bitmap, the call is
+// announced on nb once as a "bitfield" event.
+func BitField(db *store.DB, key string, ops []BitFieldOp, nb *notify.Broadcaster, dbIndex int) ([]interface{}, error) {
+	replies := make([]interface{}, len(ops))
+	wrote := false
+	_, err := db.Update(key, func(cur *store.Object) (*store.Object, error) {
+		if cur != nil && cur.Kind != store.KindString {
+			return nil, ErrWrongType
+		}
+
+		var buf []byte
+		if cur != nil {
+			buf = append([]byte(nil), cur.Str...)
+		}
+
+		for i, op := range ops {
+			buf = growBitmap(buf, op.Offset, op.Width)
+			old := getBits(buf, op.Offset, op.Width, op.Signed)
+
+			switch op.Kind {
+			case "GET":
+				replies[i] = old
+			case "SET":
+				replies[i] = old
+				setBits(buf, op.Offset, op.Width, uint64(op.Value))
+				wrote = true
+			case "INCRBY":
+				next, ok := addWithOverflow(old, op.Value, op.Width, op.Signed, op.Overflow)
+				if !ok {
+					replies[i] = nil
+					continue
+				}
+				setBits(buf, op.Offset, op.Width, uint64(next))
+				replies[i] = next
+				wrote = true
+			}
+		}
+
+		if !wrote {
+			return cur, nil
+		}
+		next := &store.Object{Kind: store.KindString, Str: buf}
+		if cur != nil {
+			next.ExpireAt = cur.ExpireAt
+		}
+		return next, nil
+	})
+	if err == nil && wrote && nb != nil {
+		nb.Publish(dbIndex, "bitfield", key)
+	}
+	return replies, err
+}
+
+// growBitmap extends buf with zero bytes, if needed, so it can hold a field
+// of the given width starting at offset. Redis auto-extends bitmap strings
+// the same way for SETBIT/BITFIELD.
+func growBitmap(buf []byte, offset uint64, width uint) []byte {
+	needed := (offset + uint64(width) + 7) / 8
+	if uint64(len(buf)) >= needed {
+		return buf
+	}
+	grown := make([]byte, needed)
+	copy(grown, buf)
+	return grown
+}
+
+func bitAt(buf []byte, pos uint64) byte {
+	byteIdx, bitIdx := pos/8, pos%8
+	return (buf[byteIdx] >> (7 - bitIdx)) & 1
+}
+
+func setBitAt(buf []byte, pos uint64, bit byte) {
+	byteIdx, bitIdx := pos/8, pos%8
+	if bit != 0 {
+		buf[byteIdx] |= 1 << (7 - bitIdx)
+	} else {
+		buf[byteIdx] &^= 1 << (7 - bitIdx)
+	}
+}
+
+// getBits reads a width-bit, big-endian field starting at offset, sign
+// extending it if signed is set.
+func getBits(buf []byte, offset uint64, width uint, signed bool) int64 {
+	var v uint64
+	for i := uint(0); i < width; i++ {
+		v = v<<1 | uint64(bitAt(buf, offset+uint64(i)))
+	}
+	if signed && width < 64 && v&(1<<(width-1)) != 0 {
+		v |= ^uint64(0) << width
+	}
+	return int64(v)
+}
+
+// setBits writes v's low width bits, big-endian, starting at offset.
+func setBits(buf []byte, offset uint64, width uint, v uint64) {
+	for i := uint(0); i < width; i++ {
+		bit := byte((v >> (width - 1 - i)) & 1)
+		setBitAt(buf, offset+uint64(i), bit)
+	}
+}
+
+// addWithOverflow adds delta to cur within a width-bit field of the given
+// signedness, applying mode when the sum falls outside the field's range.
+// ok is false only for OVERFLOW FAIL, matching BITFIELD's per-op nil reply.
+//
+// cur+delta is computed with math/big rather than plain int64 addition: cur
+// and delta are each full int64s, so for a narrow field (e.g. i32) a large
+// delta can overflow int64 itself before the field-width check ever runs,
+// silently wrapping back inside [lo, hi] and defeating SAT/FAIL detection
+// (the same class of bug numeric.go's IncrBy guards against for INCR/DECR).
+func addWithOverflow(cur, delta int64, width uint, signed bool, mode string) (result int64, ok bool) {
+	var lo, hi int64
+	if signed {
+		hi = int64(1)<<(width-1) - 1
+		lo = -hi - 1
+	} else {
+		hi = int64(1)<<width - 1
+		lo = 0
+	}
+
+	sum := new(big.Int).Add(big.NewInt(cur), big.NewInt(delta))
+	if sum.Cmp(big.NewInt(lo)) >= 0 && sum.Cmp(big.NewInt(hi)) <= 0 {
+		return sum.Int64(), true
+	}
+
+	switch mode {
+	case "SAT":
+		if sum.Sign() < 0 {
+			return lo, true
+		}
+		return hi, true
+	case "FAIL":
+		return 0, false
+	default: // WRAP
+		return wrapToField(sum, lo, hi), true
+	}
+}
+
+// wrapToField reduces sum modulo the field's span (hi-lo+1) and shifts the
+// result back into [lo, hi] -- the "truncate to width bits" behavior
+// OVERFLOW WRAP implements. big.Int keeps this exact even for a 64-bit
+// signed field, whose span (2^64) doesn't fit in an int64.
+func wrapToField(sum *big.Int, lo, hi int64) int64 {
+	span := new(big.Int).Add(new(big.Int).Sub(big.NewInt(hi), big.NewInt(lo)), big.NewInt(1))
+	rem := new(big.Int).Mod(new(big.Int).Sub(sum, big.NewInt(lo)), span)
+	return rem.Add(rem, big.NewInt(lo)).Int64()
+}
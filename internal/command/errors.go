@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+// Package command implements the server-side logic for individual Redis
+// commands against a store.DB. Handlers are plain functions rather than
+// methods on a connection object so they can be unit-tested without a RESP
+// front end; the network layer is expected to parse arguments into the
+// per-command option structs here and translate the returned errors into
+// wire replies.
+package command
+
+import "errors"
+
+var (
+	// ErrWrongType is returned when a command expects one value type (e.g.
+	// string) but the key holds another (e.g. hash or list).
+	ErrWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+	// ErrSyntax is returned for malformed or mutually-exclusive option
+	// combinations, matching Redis's generic SYNTAXERR reply.
+	ErrSyntax = errors.New("ERR syntax error")
+
+	// ErrNotInt is returned when INCR/DECR family commands find a value
+	// that isn't parseable as a base-10 int64.
+	ErrNotInt = errors.New("ERR value is not an integer or out of range")
+
+	// ErrOverflow is returned when an INCR/DECR would push the stored
+	// value outside the signed 64-bit range.
+	ErrOverflow = errors.New("ERR increment or decrement would overflow")
+
+	// ErrNotFloat is returned when INCRBYFLOAT finds a value that isn't
+	// parseable as a float.
+	ErrNotFloat = errors.New("ERR value is not a valid float")
+
+	// ErrInvalidExpireTime is returned when SET's EX/PX/EXAT/PXAT options
+	// are given a non-positive value; Redis requires a strictly positive
+	// expire time rather than silently treating it as "no TTL".
+	ErrInvalidExpireTime = errors.New("ERR invalid expire time in 'set' command")
+)
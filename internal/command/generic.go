@@ -0,0 +1,29 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+package command
+
+import (
+	"github.com/OpenAtomFoundation/pikiwidb/internal/notify"
+	"github.com/OpenAtomFoundation/pikiwidb/internal/store"
+)
+
+// Del implements DEL: it removes each of keys that actually exists, firing
+// one "del" event per key removed, and returns how many were removed.
+func Del(db *store.DB, nb *notify.Broadcaster, dbIndex int, keys ...string) int {
+	removed := 0
+	for _, key := range keys {
+		if db.Del(key) == 0 {
+			continue
+		}
+		removed++
+		if nb != nil {
+			nb.Publish(dbIndex, "del", key)
+		}
+	}
+	return removed
+}
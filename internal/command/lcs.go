@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+package command
+
+import "github.com/OpenAtomFoundation/pikiwidb/internal/store"
+
+// LCSMatch is one contiguous matching range straddling both operands, as
+// returned when LCS is called with IDX.
+type LCSMatch struct {
+	Key1Start, Key1End int
+	Key2Start, Key2End int
+	Len                int // only meaningful when WITHMATCHLEN was requested
+}
+
+// LCSResult is LCS's reply shape. MatchString is populated for the plain
+// (non-IDX) form, Matches for IDX; Len is always the overall LCS length.
+type LCSResult struct {
+	MatchString string
+	Len         int
+	Matches     []LCSMatch
+}
+
+// LCS implements the LCS command: the longest common subsequence of the
+// strings at key1 and key2. A missing key is treated as the empty string; a
+// key holding a non-string value is a WRONGTYPE error. minMatchLen filters
+// out short ranges when withIdx is set, matching MINMATCHLEN.
+//
+// LCS never mutates either key, so unlike this package's other string
+// commands it takes no notify.Broadcaster: Redis itself never fires a
+// keyspace/keyevent notification for a read-only command.
+//
+// lenOnly (LCS ... LEN without IDX) skips building the full DP table: Redis
+// values can be large, so the common length-only query is computed with two
+// rolling rows in O(min(n,m)) memory rather than paying O(n*m) just to
+// throw away everything but dp[n][m]. Reconstructing the match string or
+// index ranges genuinely needs the full table to backtrack through.
+func LCS(db *store.DB, key1, key2 string, lenOnly, withIdx bool, minMatchLen int, withMatchLen bool) (LCSResult, error) {
+	s1, err := lcsOperand(db, key1)
+	if err != nil {
+		return LCSResult{}, err
+	}
+	s2, err := lcsOperand(db, key2)
+	if err != nil {
+		return LCSResult{}, err
+	}
+
+	if lenOnly && !withIdx {
+		return LCSResult{Len: lcsLength(s1, s2)}, nil
+	}
+
+	dp := lcsTable(s1, s2)
+	result := LCSResult{Len: dp[len(s1)][len(s2)]}
+	if !withIdx {
+		result.MatchString = backtrackLCSString(s1, s2, dp)
+		return result, nil
+	}
+	result.Matches = backtrackLCSMatches(s1, s2, dp, minMatchLen, withMatchLen)
+	return result, nil
+}
+
+// lcsLength computes just the LCS length of s1 and s2 using two rolling
+// rows instead of the full (len(s1)+1) x (len(s2)+1) table.
+func lcsLength(s1, s2 string) int {
+	if len(s2) > len(s1) {
+		s1, s2 = s2, s1
+	}
+	prev := make([]int, len(s2)+1)
+	cur := make([]int, len(s2)+1)
+	for i := 1; i <= len(s1); i++ {
+		for j := 1; j <= len(s2); j++ {
+			switch {
+			case s1[i-1] == s2[j-1]:
+				cur[j] = prev[j-1] + 1
+			case prev[j] >= cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(s2)]
+}
+
+func lcsOperand(db *store.DB, key string) (string, error) {
+	obj := db.Get(key)
+	if obj == nil {
+		return "", nil
+	}
+	if obj.Kind != store.KindString {
+		return "", ErrWrongType
+	}
+	return string(obj.Str), nil
+}
+
+func lcsTable(s1, s2 string) [][]int {
+	dp := make([][]int, len(s1)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(s2)+1)
+	}
+	for i := 1; i <= len(s1); i++ {
+		for j := 1; j <= len(s2); j++ {
+			switch {
+			case s1[i-1] == s2[j-1]:
+				dp[i][j] = dp[i-1][j-1] + 1
+			case dp[i-1][j] >= dp[i][j-1]:
+				dp[i][j] = dp[i-1][j]
+			default:
+				dp[i][j] = dp[i][j-1]
+			}
+		}
+	}
+	return dp
+}
+
+// backtrackLCSString reconstructs the LCS characters by walking dp back
+// from (len(s1), len(s2)) to (0, 0).
+func backtrackLCSString(s1, s2 string, dp [][]int) string {
+	i, j := len(s1), len(s2)
+	buf := make([]byte, dp[i][j])
+	pos := len(buf)
+	for i > 0 && j > 0 {
+		switch {
+		case s1[i-1] == s2[j-1]:
+			pos--
+			buf[pos] = s1[i-1]
+			i--
+			j--
+		case dp[i-1][j] >= dp[i][j-1]:
+			i--
+		default:
+			j--
+		}
+	}
+	return string(buf)
+}
+
+// backtrackLCSMatches walks dp like backtrackLCSString but groups runs of
+// consecutive matched characters into ranges, emitting them in the order
+// backtracking finds them (the end of the strings first), same as Redis.
+func backtrackLCSMatches(s1, s2 string, dp [][]int, minMatchLen int, withLen bool) []LCSMatch {
+	var matches []LCSMatch
+	var cur *LCSMatch
+
+	flush := func() {
+		if cur != nil && cur.Len >= minMatchLen {
+			if !withLen {
+				cur.Len = 0
+			}
+			matches = append(matches, *cur)
+		}
+		cur = nil
+	}
+
+	i, j := len(s1), len(s2)
+	for i > 0 && j > 0 {
+		switch {
+		case s1[i-1] == s2[j-1]:
+			if cur == nil {
+				cur = &LCSMatch{Key1End: i - 1, Key2End: j - 1}
+			}
+			cur.Key1Start, cur.Key2Start = i-1, j-1
+			cur.Len++
+			i--
+			j--
+		case dp[i-1][j] >= dp[i][j-1]:
+			flush()
+			i--
+		default:
+			flush()
+			j--
+		}
+	}
+	flush()
+	return matches
+}
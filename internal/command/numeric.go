@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+package command
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/OpenAtomFoundation/pikiwidb/internal/notify"
+	"github.com/OpenAtomFoundation/pikiwidb/internal/store"
+)
+
+// IncrBy implements INCR/DECR/INCRBY/DECRBY: it atomically adds delta to
+// the integer stored at key (treating a missing key as 0) and returns the
+// new value. It reports ErrNotInt if the existing value isn't a base-10
+// int64, and ErrOverflow if delta would push the result out of range. On
+// success it announces event ("incrby" or "decrby", per the calling
+// command) on nb.
+func IncrBy(db *store.DB, key string, delta int64, event string, nb *notify.Broadcaster, dbIndex int) (int64, error) {
+	var result int64
+	_, err := db.Update(key, func(cur *store.Object) (*store.Object, error) {
+		if cur != nil && cur.Kind != store.KindString {
+			return nil, ErrWrongType
+		}
+
+		var n int64
+		if cur != nil {
+			var perr error
+			n, perr = strconv.ParseInt(string(cur.Str), 10, 64)
+			if perr != nil {
+				return nil, ErrNotInt
+			}
+		}
+		if (delta > 0 && n > math.MaxInt64-delta) || (delta < 0 && n < math.MinInt64-delta) {
+			return nil, ErrOverflow
+		}
+		result = n + delta
+
+		return &store.Object{Kind: store.KindString, Str: []byte(strconv.FormatInt(result, 10)), ExpireAt: expireOf(cur)}, nil
+	})
+	if err == nil && nb != nil {
+		nb.Publish(dbIndex, event, key)
+	}
+	return result, err
+}
+
+// IncrByFloat implements INCRBYFLOAT: it adds delta to the float stored at
+// key (treating a missing key as 0) and stores the result formatted in
+// fixed-point with no trailing zeros, same as Redis. NaN/Inf results are
+// rejected rather than stored. A successful call is announced on nb as an
+// "incrbyfloat" event.
+func IncrByFloat(db *store.DB, key string, delta float64, nb *notify.Broadcaster, dbIndex int) (float64, error) {
+	var result float64
+	_, err := db.Update(key, func(cur *store.Object) (*store.Object, error) {
+		if cur != nil && cur.Kind != store.KindString {
+			return nil, ErrWrongType
+		}
+
+		var f float64
+		if cur != nil {
+			var perr error
+			f, perr = strconv.ParseFloat(string(cur.Str), 64)
+			if perr != nil {
+				return nil, ErrNotFloat
+			}
+		}
+		result = f + delta
+		if math.IsNaN(result) || math.IsInf(result, 0) {
+			return nil, ErrNotFloat
+		}
+
+		return &store.Object{Kind: store.KindString, Str: []byte(formatFloat(result)), ExpireAt: expireOf(cur)}, nil
+	})
+	if err == nil && nb != nil {
+		nb.Publish(dbIndex, "incrbyfloat", key)
+	}
+	return result, err
+}
+
+func expireOf(cur *store.Object) time.Time {
+	if cur == nil {
+		return time.Time{}
+	}
+	return cur.ExpireAt
+}
+
+// formatFloat renders f the way INCRBYFLOAT does: fixed-point, no exponent,
+// and no digits beyond what's needed to round-trip the value.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+package command
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenAtomFoundation/pikiwidb/internal/notify"
+	"github.com/OpenAtomFoundation/pikiwidb/internal/store"
+)
+
+// SetOptions is the parsed option matrix accepted by SET, mirroring
+// https://redis.io/commands/set/.
+type SetOptions struct {
+	Mode     string // "", "NX" or "XX"
+	TTL      time.Duration
+	ExpireAt time.Time
+	KeepTTL  bool
+	Get      bool
+}
+
+// ParseSetArgs parses the option tokens following SET's key/value pair. It
+// returns ErrSyntax for the combinations Redis itself rejects: NX with XX,
+// or more than one of EX/PX/EXAT/PXAT/KEEPTTL.
+func ParseSetArgs(args []string) (SetOptions, error) {
+	var opts SetOptions
+	hasExpireOpt := false
+
+	for i := 0; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX", "XX":
+			if opts.Mode != "" {
+				return SetOptions{}, ErrSyntax
+			}
+			opts.Mode = strings.ToUpper(args[i])
+
+		case "GET":
+			opts.Get = true
+
+		case "KEEPTTL":
+			if hasExpireOpt {
+				return SetOptions{}, ErrSyntax
+			}
+			hasExpireOpt = true
+			opts.KeepTTL = true
+
+		case "EX", "PX", "EXAT", "PXAT":
+			if hasExpireOpt {
+				return SetOptions{}, ErrSyntax
+			}
+			hasExpireOpt = true
+			if i+1 >= len(args) {
+				return SetOptions{}, ErrSyntax
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				return SetOptions{}, ErrSyntax
+			}
+			if n <= 0 {
+				return SetOptions{}, ErrInvalidExpireTime
+			}
+			switch strings.ToUpper(args[i]) {
+			case "EX":
+				opts.TTL = time.Duration(n) * time.Second
+			case "PX":
+				opts.TTL = time.Duration(n) * time.Millisecond
+			case "EXAT":
+				opts.ExpireAt = time.Unix(n, 0)
+			case "PXAT":
+				opts.ExpireAt = time.UnixMilli(n)
+			}
+			i++
+
+		default:
+			return SetOptions{}, ErrSyntax
+		}
+	}
+	return opts, nil
+}
+
+// Set implements SET, including its full option matrix. prev/hadPrev report
+// the key's previous value for the GET option; applied is false when an
+// NX/XX precondition wasn't met, in which case no mutation happens. A
+// successful mutation is announced on nb as a "set" event, for
+// notify-keyspace-events subscribers.
+func Set(db *store.DB, key, value string, opts SetOptions, nb *notify.Broadcaster, dbIndex int) (prev string, hadPrev, applied bool, err error) {
+	_, err = db.Update(key, func(cur *store.Object) (*store.Object, error) {
+		if opts.Get {
+			if cur != nil && cur.Kind != store.KindString {
+				return nil, ErrWrongType
+			}
+			if cur != nil {
+				prev, hadPrev = string(cur.Str), true
+			}
+		}
+
+		switch {
+		case opts.Mode == "NX" && cur != nil:
+			applied = false
+			return cur, nil
+		case opts.Mode == "XX" && cur == nil:
+			applied = false
+			return cur, nil
+		}
+		applied = true
+
+		next := &store.Object{Kind: store.KindString, Str: []byte(value)}
+		switch {
+		case opts.KeepTTL && cur != nil:
+			next.ExpireAt = cur.ExpireAt
+		case !opts.ExpireAt.IsZero():
+			next.ExpireAt = opts.ExpireAt
+		case opts.TTL > 0:
+			next.ExpireAt = time.Now().Add(opts.TTL)
+		}
+		return next, nil
+	})
+	if err == nil && applied && nb != nil {
+		nb.Publish(dbIndex, "set", key)
+	}
+	return prev, hadPrev, applied, err
+}
+
+// Get implements GET: ok is false when the key doesn't exist.
+func Get(db *store.DB, key string) (value string, ok bool, err error) {
+	obj := db.Get(key)
+	if obj == nil {
+		return "", false, nil
+	}
+	if obj.Kind != store.KindString {
+		return "", false, ErrWrongType
+	}
+	return string(obj.Str), true, nil
+}
+
+// Append implements APPEND: it appends value to the string at key
+// (creating key if it's absent) and returns the resulting length. A
+// successful call fires an "append" event.
+func Append(db *store.DB, key, value string, nb *notify.Broadcaster, dbIndex int) (length int, err error) {
+	_, err = db.Update(key, func(cur *store.Object) (*store.Object, error) {
+		if cur != nil && cur.Kind != store.KindString {
+			return nil, ErrWrongType
+		}
+		buf := append([]byte(nil), value...)
+		if cur != nil {
+			buf = append(append([]byte(nil), cur.Str...), value...)
+		}
+		length = len(buf)
+
+		next := &store.Object{Kind: store.KindString, Str: buf}
+		if cur != nil {
+			next.ExpireAt = cur.ExpireAt
+		}
+		return next, nil
+	})
+	if err == nil && nb != nil {
+		nb.Publish(dbIndex, "append", key)
+	}
+	return length, err
+}
+
+// GetSet implements GETSET: it atomically swaps the string at key for
+// value and returns the prior value (hadPrev is false if key didn't
+// exist). A successful call fires a "set" event, the same as Redis does
+// for GETSET.
+func GetSet(db *store.DB, key, value string, nb *notify.Broadcaster, dbIndex int) (prev string, hadPrev bool, err error) {
+	_, err = db.Update(key, func(cur *store.Object) (*store.Object, error) {
+		if cur != nil && cur.Kind != store.KindString {
+			return nil, ErrWrongType
+		}
+		if cur != nil {
+			prev, hadPrev = string(cur.Str), true
+		}
+		return &store.Object{Kind: store.KindString, Str: []byte(value)}, nil
+	})
+	if err == nil && nb != nil {
+		nb.Publish(dbIndex, "set", key)
+	}
+	return prev, hadPrev, err
+}
+
+// KVPair is one key/value pair in an MSET/MSETNX call. It's a slice of
+// pairs rather than a map so the pairs are set in the order they were
+// given, same as Redis.
+type KVPair struct {
+	Key   string
+	Value string
+}
+
+// MSet implements MSET: it unconditionally sets every pair (clearing any
+// existing TTL, as SET without KEEPTTL does) and fires one "set" event per
+// key.
+func MSet(db *store.DB, pairs []KVPair, nb *notify.Broadcaster, dbIndex int) {
+	for _, p := range pairs {
+		db.Update(p.Key, func(cur *store.Object) (*store.Object, error) {
+			return &store.Object{Kind: store.KindString, Str: []byte(p.Value)}, nil
+		})
+		if nb != nil {
+			nb.Publish(dbIndex, "set", p.Key)
+		}
+	}
+}
+
+// MSetNX implements MSETNX: it sets every pair only if none of their keys
+// already exist, firing one "set" event per key; if any key already
+// exists, it sets nothing and fires nothing.
+func MSetNX(db *store.DB, pairs []KVPair, nb *notify.Broadcaster, dbIndex int) bool {
+	for _, p := range pairs {
+		if db.Get(p.Key) != nil {
+			return false
+		}
+	}
+	for _, p := range pairs {
+		db.Update(p.Key, func(cur *store.Object) (*store.Object, error) {
+			return &store.Object{Kind: store.KindString, Str: []byte(p.Value)}, nil
+		})
+		if nb != nil {
+			nb.Publish(dbIndex, "set", p.Key)
+		}
+	}
+	return true
+}
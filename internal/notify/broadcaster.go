@@ -0,0 +1,231 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+// Package notify implements Redis-compatible keyspace/keyevent notifications:
+// https://redis.io/docs/latest/develop/use/keyspace-notifications/
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/OpenAtomFoundation/pikiwidb/internal/pubsub"
+)
+
+// subscriberBuffer is the number of pending events a subscriber can queue
+// before Publish starts dropping events for it rather than blocking.
+const subscriberBuffer = 100
+
+// Event is a single keyspace/keyevent notification, already rendered as the
+// two PSUBSCRIBE-visible channels a subscriber cares about.
+type Event struct {
+	DB              int
+	Event           string
+	Key             string
+	KeyspaceChannel string // __keyspace@<db>__:<key>, payload is Event
+	KeyeventChannel string // __keyevent@<db>__:<event>, payload is Key
+}
+
+// classOf reports the Redis notification class letter for a given event
+// name, e.g. "set" is a string ('$') event and "del" is a generic ('g') one.
+// Unknown events are treated as generic.
+func classOf(event string) byte {
+	switch event {
+	case "set", "append", "getset", "mset", "msetnx",
+		"incrby", "incrbyfloat", "decrby", "bitfield", "lcs":
+		return '$'
+	default:
+		return 'g'
+	}
+}
+
+// Config is the parsed form of the `notify-keyspace-events` flag string,
+// e.g. "KEA" or "K$g". It mirrors Redis's own letter mask.
+type Config struct {
+	Keyspace bool
+	Keyevent bool
+	Classes  map[byte]bool
+}
+
+// ParseConfig parses a `notify-keyspace-events` flag string. It returns an
+// error for unrecognized letters so misconfiguration is caught at set time
+// rather than silently discarding events later.
+func ParseConfig(flags string) (Config, error) {
+	cfg := Config{Classes: map[byte]bool{}}
+	for _, c := range flags {
+		switch c {
+		case 'K':
+			cfg.Keyspace = true
+		case 'E':
+			cfg.Keyevent = true
+		case 'A':
+			for _, class := range "g$lshzxet" {
+				cfg.Classes[byte(class)] = true
+			}
+		case 'g', '$', 'l', 's', 'h', 'z', 'x', 'e', 't', 'd', 'm', 'n':
+			cfg.Classes[byte(c)] = true
+		default:
+			return Config{}, fmt.Errorf("unknown notify-keyspace-events flag %q", string(c))
+		}
+	}
+	return cfg, nil
+}
+
+func (cfg Config) enabled(class byte) bool {
+	return (cfg.Keyspace || cfg.Keyevent) && cfg.Classes[class]
+}
+
+// Broadcaster renders keyspace/keyevent notifications and publishes them on
+// a pubsub.Hub, the same broker PSUBSCRIBE/PUBLISH/SUBSCRIBE use, so a
+// client's `PSUBSCRIBE __keyspace@<db>__:*` / `__keyevent@<db>__:*` sees
+// them like any other published message. It also keeps its own in-process
+// fan-out (Subscribe) for Go-side consumers (e.g. replication/AOF hooks)
+// that want raw Events rather than rendered channel/payload strings.
+type Broadcaster struct {
+	mu     sync.Mutex
+	nextID uint64
+	subs   map[uint64]chan Event
+	hub    *pubsub.Hub
+	cfg    Config
+}
+
+// NewBroadcaster returns a Broadcaster with notifications disabled; call
+// SetConfig with a `notify-keyspace-events` flag string to enable them.
+// Rendered events are published on hub, which is also what serves clients'
+// PSUBSCRIBE/SUBSCRIBE commands.
+func NewBroadcaster(hub *pubsub.Hub) *Broadcaster {
+	return &Broadcaster{subs: make(map[uint64]chan Event), hub: hub}
+}
+
+// SetConfig updates which event classes and channels are published. It is
+// safe to call concurrently with Publish and Subscribe.
+func (b *Broadcaster) SetConfig(cfg Config) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = cfg
+}
+
+// Subscribe registers a new subscriber and returns a receive-only channel of
+// events. The subscriber is unregistered and its channel closed as soon as
+// ctx is done; callers must keep draining the channel until it closes.
+func (b *Broadcaster) Subscribe(ctx context.Context) (<-chan Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, id)
+		b.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// Publish notifies subscribers of a mutation to key in db. It is a no-op if
+// the event's class is not enabled via SetConfig. A subscriber whose buffer
+// is full has this event dropped for it rather than blocking the publisher,
+// and the same goes for the hub's own PSUBSCRIBE/SUBSCRIBE clients.
+func (b *Broadcaster) Publish(db int, event, key string) {
+	class := classOf(event)
+
+	b.mu.Lock()
+	cfg := b.cfg
+	if !cfg.enabled(class) {
+		b.mu.Unlock()
+		return
+	}
+
+	e := Event{
+		DB:              db,
+		Event:           event,
+		Key:             key,
+		KeyspaceChannel: fmt.Sprintf("__keyspace@%d__:%s", db, key),
+		KeyeventChannel: fmt.Sprintf("__keyevent@%d__:%s", db, event),
+	}
+	if !cfg.Keyspace {
+		e.KeyspaceChannel = ""
+	}
+	if !cfg.Keyevent {
+		e.KeyeventChannel = ""
+	}
+
+	// Hold the lock across the send: Subscribe's cleanup goroutine also takes
+	// it before closing a subscriber's channel, so a subscriber can never be
+	// closed while we're mid-send to it. Sends are non-blocking (default
+	// case), so this doesn't stall other subscribers or the caller.
+	for _, ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+			// Slow consumer: drop this event rather than block the publisher.
+		}
+	}
+	b.mu.Unlock()
+
+	// Render onto the shared pub/sub hub last and without the lock held:
+	// this is what makes the notification visible to a real client's
+	// PSUBSCRIBE __keyspace@<db>__:* / __keyevent@<db>__:*, same as any
+	// other PUBLISH.
+	if b.hub == nil {
+		return
+	}
+	if e.KeyspaceChannel != "" {
+		b.hub.Publish(e.KeyspaceChannel, e.Event)
+	}
+	if e.KeyeventChannel != "" {
+		b.hub.Publish(e.KeyeventChannel, e.Key)
+	}
+}
+
+// String renders the currently active configuration back into Redis's own
+// flag-string form, e.g. "KEA".
+func (cfg Config) String() string {
+	var sb strings.Builder
+	if cfg.Keyspace {
+		sb.WriteByte('K')
+	}
+	if cfg.Keyevent {
+		sb.WriteByte('E')
+	}
+	all := true
+	for _, class := range "g$lshzxet" {
+		if !cfg.Classes[byte(class)] {
+			all = false
+			break
+		}
+	}
+	if all && len(cfg.Classes) > 0 {
+		sb.WriteByte('A')
+	} else {
+		for _, class := range "g$lshzxet" {
+			if cfg.Classes[byte(class)] {
+				sb.WriteByte(byte(class))
+			}
+		}
+	}
+	// d/m/n aren't part of the "A" shorthand in Redis either, so they're
+	// always rendered explicitly regardless of whether "A" was written above.
+	for _, class := range "dmn" {
+		if cfg.Classes[byte(class)] {
+			sb.WriteByte(byte(class))
+		}
+	}
+	return sb.String()
+}
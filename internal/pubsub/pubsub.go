@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+// Package pubsub implements the generic Redis PUBLISH/SUBSCRIBE/PSUBSCRIBE
+// broker: any number of subscribers, each bound to either one exact channel
+// or a glob pattern, receive every message published to a channel they
+// match. This is the machinery notify.Broadcaster renders keyspace/keyevent
+// notifications through, so a client's PSUBSCRIBE __keyspace@<db>__:* sees
+// them exactly like any other published message.
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBuffer is the number of pending messages a subscriber can queue
+// before Publish starts dropping messages for it rather than blocking.
+const subscriberBuffer = 100
+
+// Message is a single delivery to a subscriber. Pattern is empty for a
+// plain SUBSCRIBE and set to the matching pattern for a PSUBSCRIBE.
+type Message struct {
+	Pattern string
+	Channel string
+	Payload string
+}
+
+type subscriber struct {
+	channel string // set for an exact-channel SUBSCRIBE
+	pattern string // set for a PSUBSCRIBE
+	ch      chan Message
+}
+
+// Hub fans published messages out to matching subscribers. Each subscriber
+// gets its own buffered channel, so a slow consumer can never block the
+// publisher or other subscribers (mirroring notify.Broadcaster's design).
+type Hub struct {
+	mu     sync.Mutex
+	nextID uint64
+	subs   map[uint64]*subscriber
+}
+
+// NewHub returns an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[uint64]*subscriber)}
+}
+
+// Subscribe registers interest in one exact channel name (SUBSCRIBE).
+func (h *Hub) Subscribe(ctx context.Context, channel string) (<-chan Message, error) {
+	return h.add(ctx, channel, "")
+}
+
+// PSubscribe registers interest in every channel matching a glob pattern
+// (PSUBSCRIBE), e.g. "__keyspace@0__:*".
+func (h *Hub) PSubscribe(ctx context.Context, pattern string) (<-chan Message, error) {
+	return h.add(ctx, "", pattern)
+}
+
+func (h *Hub) add(ctx context.Context, channel, pattern string) (<-chan Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Message, subscriberBuffer)
+	sub := &subscriber{channel: channel, pattern: pattern, ch: ch}
+
+	h.mu.Lock()
+	id := h.nextID
+	h.nextID++
+	h.subs[id] = sub
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		delete(h.subs, id)
+		h.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// Publish delivers payload to every subscriber whose channel or pattern
+// matches channel, returning how many subscribers received it. A
+// subscriber whose buffer is full has this message dropped for it rather
+// than blocking the publisher.
+func (h *Hub) Publish(channel, payload string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delivered := 0
+	for _, sub := range h.subs {
+		matched := sub.channel == channel
+		if sub.pattern != "" {
+			matched = globMatch(sub.pattern, channel)
+		}
+		if !matched {
+			continue
+		}
+		select {
+		case sub.ch <- Message{Pattern: sub.pattern, Channel: channel, Payload: payload}:
+			delivered++
+		default:
+			// Slow consumer: drop this message rather than block the publisher.
+		}
+	}
+	return delivered
+}
+
+// globMatch reports whether s matches the Redis-style glob pattern: '*'
+// matches any run of characters, '?' matches exactly one, "[...]" matches a
+// character class (optionally negated with a leading '^', and supporting
+// "a-z" ranges), and '\' escapes the following character.
+func globMatch(pattern, s string) bool {
+	return globMatchHere([]byte(pattern), []byte(s))
+}
+
+func globMatchHere(pattern, s []byte) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if globMatchHere(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			end := indexByte(pattern, ']')
+			if end < 0 {
+				// No closing bracket: treat '[' as a literal.
+				if pattern[0] != s[0] {
+					return false
+				}
+				pattern, s = pattern[1:], s[1:]
+				continue
+			}
+			class := pattern[1:end]
+			negate := len(class) > 0 && class[0] == '^'
+			if negate {
+				class = class[1:]
+			}
+			if classContains(class, s[0]) == negate {
+				return false
+			}
+			pattern, s = pattern[end+1:], s[1:]
+
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		}
+	}
+	return len(s) == 0
+}
+
+func classContains(class []byte, c byte) bool {
+	for i := 0; i < len(class); i++ {
+		if i+2 < len(class) && class[i+1] == '-' {
+			if class[i] <= c && c <= class[i+2] {
+				return true
+			}
+			i += 2
+			continue
+		}
+		if class[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+func indexByte(b []byte, c byte) int {
+	for i, x := range b {
+		if x == c {
+			return i
+		}
+	}
+	return -1
+}
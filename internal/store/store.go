@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023-present, OpenAtom Foundation, Inc.  All rights reserved.
+ * This source code is licensed under the BSD-style license found in the
+ * LICENSE file in the root directory of this source tree. An additional grant
+ * of patent rights can be found in the PATENTS file in the same directory.
+ */
+
+// Package store is the in-memory keyspace that command handlers operate on:
+// a single flat key -> typed value map with lazy TTL expiry.
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// Kind identifies the Redis type an Object holds. Command handlers compare
+// a key's Kind against the type they expect and return ErrWrongType (in
+// package command) on a mismatch.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindHash
+	KindList
+)
+
+// Object is the value stored under a single key. Only the field matching
+// Kind is meaningful; the others are left zero.
+type Object struct {
+	Kind     Kind
+	Str      []byte
+	Hash     map[string]string
+	List     []string
+	ExpireAt time.Time // zero value means the key has no TTL
+}
+
+// DB is a single Redis-style keyspace (what SELECT switches between).
+type DB struct {
+	mu   sync.Mutex
+	data map[string]*Object
+}
+
+// NewDB returns an empty DB.
+func NewDB() *DB {
+	return &DB{data: make(map[string]*Object)}
+}
+
+// Get returns the live object stored at key, or nil if it doesn't exist or
+// has expired. An expired key is lazily deleted on access.
+func (db *DB) Get(key string) *Object {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.getLocked(key)
+}
+
+func (db *DB) getLocked(key string) *Object {
+	obj, ok := db.data[key]
+	if !ok {
+		return nil
+	}
+	if !obj.ExpireAt.IsZero() && time.Now().After(obj.ExpireAt) {
+		delete(db.data, key)
+		return nil
+	}
+	return obj
+}
+
+// Del removes keys, returning how many were actually present.
+func (db *DB) Del(keys ...string) int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	n := 0
+	for _, key := range keys {
+		if db.getLocked(key) != nil {
+			delete(db.data, key)
+			n++
+		}
+	}
+	return n
+}
+
+// TTL reports the remaining time-to-live for key: ok is false if the key
+// doesn't exist, and the returned duration is -1 if the key exists but has
+// no expiry set.
+func (db *DB) TTL(key string) (ttl time.Duration, ok bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	obj := db.getLocked(key)
+	if obj == nil {
+		return 0, false
+	}
+	if obj.ExpireAt.IsZero() {
+		return -1, true
+	}
+	return time.Until(obj.ExpireAt), true
+}
+
+// Update atomically loads the current object at key (nil if absent or
+// expired) and replaces it with whatever fn returns. Returning a nil object
+// deletes the key; returning an error applies no mutation at all. Command
+// handlers use this instead of Get+Set to make read-modify-write commands
+// like SET's NX/XX modes and INCRBY race-free.
+func (db *DB) Update(key string, fn func(cur *Object) (*Object, error)) (*Object, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	cur := db.getLocked(key)
+	next, err := fn(cur)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		delete(db.data, key)
+	} else {
+		db.data[key] = next
+	}
+	return next, nil
+}
@@ -10,6 +10,7 @@ package pikiwidb_test
 import (
 	"context"
 	"log"
+	"math"
 	"strconv"
 	"time"
 
@@ -20,6 +21,12 @@ import (
 	"github.com/OpenAtomFoundation/pikiwidb/tests/util"
 )
 
+// syntaxErrMatcher matches the `SYNTAXERR` reply returned when a command is
+// given a malformed or mutually-exclusive combination of options.
+func syntaxErrMatcher() OmegaMatcher {
+	return MatchError(ContainSubstring("syntax error"))
+}
+
 var _ = Describe("String", Ordered, func() {
 	var (
 		ctx    = context.TODO()
@@ -139,8 +146,220 @@ var _ = Describe("String", Ordered, func() {
 		}
 	})
 
+	It("Cmd SET with EX/PX/EXAT/PXAT", func() {
+		r, e := client.SetArgs(ctx, DefaultKey, "hello", redis.SetArgs{TTL: 100 * time.Second}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+		ttl, e := client.TTL(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(ttl).To(BeNumerically("~", 100*time.Second, 3*time.Second))
+
+		r, e = client.Do(ctx, "SET", DefaultKey, "hello", "PX", "100000").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+		pttl, e := client.PTTL(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(pttl).To(BeNumerically("~", 100*time.Second, 3*time.Second))
+
+		r, e = client.SetArgs(ctx, DefaultKey, "hello", redis.SetArgs{ExpireAt: time.Now().Add(100 * time.Second)}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+		ttl, e = client.TTL(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(ttl).To(BeNumerically("~", 100*time.Second, 3*time.Second))
+
+		pxat := strconv.FormatInt(time.Now().Add(100*time.Second).UnixMilli(), 10)
+		r, e = client.Do(ctx, "SET", DefaultKey, "hello", "PXAT", pxat).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+		pttl, e = client.PTTL(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(pttl).To(BeNumerically("~", 100*time.Second, 3*time.Second))
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
+	It("Cmd SET with NX/XX", func() {
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+
+		r, e := client.SetArgs(ctx, DefaultKey, "v1", redis.SetArgs{Mode: "XX"}).Result()
+		Expect(e).To(MatchError(redis.Nil))
+		Expect(r).To(Equal(Nil))
+
+		r, e = client.SetArgs(ctx, DefaultKey, "v1", redis.SetArgs{Mode: "NX"}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+
+		r, e = client.SetArgs(ctx, DefaultKey, "v2", redis.SetArgs{Mode: "NX"}).Result()
+		Expect(e).To(MatchError(redis.Nil))
+		Expect(r).To(Equal(Nil))
+		get, e := client.Get(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(get).To(Equal("v1"))
+
+		r, e = client.SetArgs(ctx, DefaultKey, "v3", redis.SetArgs{Mode: "XX"}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+		get, e = client.Get(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(get).To(Equal("v3"))
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
+	It("Cmd SET with KEEPTTL", func() {
+		r, e := client.SetArgs(ctx, DefaultKey, "v1", redis.SetArgs{TTL: 100 * time.Second}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+
+		r, e = client.SetArgs(ctx, DefaultKey, "v2", redis.SetArgs{KeepTTL: true}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+		ttl, e := client.TTL(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(ttl).To(BeNumerically("~", 100*time.Second, 3*time.Second))
+
+		r, e = client.Set(ctx, DefaultKey, "v3", 0).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(OK))
+		ttl, e = client.TTL(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(ttl).To(Equal(time.Duration(-1)))
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
+	It("Cmd SET with GET", func() {
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+
+		r, e := client.SetArgs(ctx, DefaultKey, "v1", redis.SetArgs{Get: true}).Result()
+		Expect(e).To(MatchError(redis.Nil))
+		Expect(r).To(Equal(Nil))
+
+		r, e = client.SetArgs(ctx, DefaultKey, "v2", redis.SetArgs{Get: true}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal("v1"))
+
+		r, e = client.SetArgs(ctx, DefaultKey, "v3", redis.SetArgs{Mode: "NX", Get: true}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal("v2"))
+		get, e := client.Get(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(get).To(Equal("v2"))
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+		Expect(client.LPush(ctx, DefaultKey, "v1").Err()).NotTo(HaveOccurred())
+		_, e = client.SetArgs(ctx, DefaultKey, "v2", redis.SetArgs{Get: true}).Result()
+		Expect(e).To(MatchError(ContainSubstring("WRONGTYPE")))
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
+	It("Cmd SET rejects conflicting options with SYNTAXERR", func() {
+		_, e := client.Do(ctx, "SET", DefaultKey, "v1", "NX", "XX").Result()
+		Expect(e).To(syntaxErrMatcher())
+
+		_, e = client.Do(ctx, "SET", DefaultKey, "v1", "EX", "10", "PX", "10000").Result()
+		Expect(e).To(syntaxErrMatcher())
+
+		_, e = client.Do(ctx, "SET", DefaultKey, "v1", "EX", "10", "KEEPTTL").Result()
+		Expect(e).To(syntaxErrMatcher())
+
+		_, e = client.Do(ctx, "SET", DefaultKey, "v1", "KEEPTTL", "PXAT", "123").Result()
+		Expect(e).To(syntaxErrMatcher())
+	})
+
+	It("Cmd SET rejects non-positive EX/PX/EXAT/PXAT", func() {
+		_, e := client.Do(ctx, "SET", DefaultKey, "v1", "EX", "0").Result()
+		Expect(e).To(MatchError(ContainSubstring("invalid expire time")))
+
+		_, e = client.Do(ctx, "SET", DefaultKey, "v1", "PX", "-1").Result()
+		Expect(e).To(MatchError(ContainSubstring("invalid expire time")))
+
+		_, e = client.Do(ctx, "SET", DefaultKey, "v1", "EXAT", "0").Result()
+		Expect(e).To(MatchError(ContainSubstring("invalid expire time")))
+
+		_, e = client.Do(ctx, "SET", DefaultKey, "v1", "PXAT", "-100").Result()
+		Expect(e).To(MatchError(ContainSubstring("invalid expire time")))
+	})
+
 	It("Cmd INCR", func() {
 		log.Println("Cmd INCR Test Begin")
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+
+		r, e := client.Incr(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(int64(1)))
+		get, e := client.Get(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(get).To(Equal("1"))
+
+		r, e = client.IncrBy(ctx, DefaultKey, 9).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(int64(10)))
+
+		r, e = client.DecrBy(ctx, DefaultKey, 4).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(int64(6)))
+
+		r, e = client.Decr(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal(int64(5)))
+
+		// Wrong-type errors against a hash/list key.
+		Expect(client.Del(ctx, "incr_hash_key", "incr_list_key").Err()).NotTo(HaveOccurred())
+		Expect(client.HSet(ctx, "incr_hash_key", "f", "v").Err()).NotTo(HaveOccurred())
+		_, e = client.Incr(ctx, "incr_hash_key").Result()
+		Expect(e).To(MatchError(ContainSubstring("WRONGTYPE")))
+		Expect(client.LPush(ctx, "incr_list_key", "v").Err()).NotTo(HaveOccurred())
+		_, e = client.Incr(ctx, "incr_list_key").Result()
+		Expect(e).To(MatchError(ContainSubstring("WRONGTYPE")))
+
+		// Non-integer existing value.
+		Expect(client.Set(ctx, DefaultKey, "not_a_number", 0).Err()).NotTo(HaveOccurred())
+		_, e = client.Incr(ctx, DefaultKey).Result()
+		Expect(e).To(MatchError(ContainSubstring("value is not an integer or out of range")))
+
+		// Overflow, both directions.
+		Expect(client.Set(ctx, DefaultKey, strconv.FormatInt(math.MaxInt64, 10), 0).Err()).NotTo(HaveOccurred())
+		_, e = client.Incr(ctx, DefaultKey).Result()
+		Expect(e).To(MatchError(ContainSubstring("increment or decrement would overflow")))
+
+		Expect(client.Set(ctx, DefaultKey, strconv.FormatInt(math.MinInt64, 10), 0).Err()).NotTo(HaveOccurred())
+		_, e = client.Decr(ctx, DefaultKey).Result()
+		Expect(e).To(MatchError(ContainSubstring("increment or decrement would overflow")))
+
+		Expect(client.Del(ctx, DefaultKey, "incr_hash_key", "incr_list_key").Err()).NotTo(HaveOccurred())
+	})
+
+	It("Cmd INCRBYFLOAT", func() {
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+
+		f, e := client.IncrByFloat(ctx, DefaultKey, 10.50).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(f).To(Equal(10.5))
+
+		f, e = client.IncrByFloat(ctx, DefaultKey, 0.1).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(f).To(Equal(10.6))
+
+		// Stored with no exponent notation even if the original value used it.
+		Expect(client.Set(ctx, DefaultKey, "3.0e3", 0).Err()).NotTo(HaveOccurred())
+		f, e = client.IncrByFloat(ctx, DefaultKey, 200).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(f).To(Equal(3200.0))
+		get, e := client.Get(ctx, DefaultKey).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(get).NotTo(ContainSubstring("e"))
+		Expect(get).NotTo(ContainSubstring("E"))
+		Expect(get).To(Equal("3200"))
+
+		// NaN/Inf results are rejected.
+		Expect(client.Set(ctx, DefaultKey, "0", 0).Err()).NotTo(HaveOccurred())
+		_, e = client.IncrByFloat(ctx, DefaultKey, math.Inf(1)).Result()
+		Expect(e).To(HaveOccurred())
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
 	})
 
 	It("Append", func() {
@@ -194,6 +413,170 @@ var _ = Describe("String", Ordered, func() {
 		Expect(rDel).To(Equal(int64(1)))
 	})
 
+	It("BitField GET/SET/INCRBY", func() {
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+
+		r, e := client.BitField(ctx, DefaultKey, "SET", "u8", "0", "255").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{0}))
+
+		r, e = client.BitField(ctx, DefaultKey, "GET", "u8", "0").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{255}))
+
+		// Unaligned offset crossing a byte boundary.
+		r, e = client.BitField(ctx, DefaultKey, "SET", "u8", "4", "15").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{240}))
+
+		// "#N" addressing means N * width, auto-extending the string with zero bytes.
+		r, e = client.BitField(ctx, DefaultKey, "SET", "u8", "#2", "7").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{0}))
+		r, e = client.BitField(ctx, DefaultKey, "GET", "u8", "#2").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{7}))
+
+		// Signed reads sign-extend from the top bit of the field.
+		_, e = client.BitField(ctx, DefaultKey, "SET", "i8", "#2", "-1").Result()
+		Expect(e).NotTo(HaveOccurred())
+		r, e = client.BitField(ctx, DefaultKey, "GET", "i8", "#2").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{-1}))
+
+		r, e = client.BitField(ctx, DefaultKey, "INCRBY", "u8", "#2", "-10").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{245}))
+
+		// A single call can mix multiple sub-ops; the reply is an array of the same length.
+		r, e = client.BitField(ctx, DefaultKey, "SET", "u8", "0", "1", "GET", "u8", "0", "INCRBY", "u8", "0", "1").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{240, 1, 2}))
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
+	It("BitField OVERFLOW modes", func() {
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+
+		// WRAP (the default): two's-complement wrap on overflow.
+		r, e := client.BitField(ctx, DefaultKey, "SET", "u8", "0", "255").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{0}))
+		r, e = client.BitField(ctx, DefaultKey, "INCRBY", "u8", "0", "10").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{9}))
+
+		// SAT: clamp to the type's bounds instead of wrapping.
+		r, e = client.BitField(ctx, DefaultKey, "SET", "u8", "0", "255", "OVERFLOW", "SAT", "INCRBY", "u8", "0", "10").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{9, 255}))
+
+		r, e = client.BitField(ctx, DefaultKey, "SET", "i8", "0", "-128", "OVERFLOW", "SAT", "INCRBY", "i8", "0", "-10").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r[1]).To(Equal(int64(-128)))
+
+		// FAIL: the failing sub-op returns nil but prior sub-ops in the call still apply.
+		rFail, e := client.Do(ctx, "BITFIELD", DefaultKey, "SET", "u8", "0", "200", "OVERFLOW", "FAIL", "INCRBY", "u8", "0", "100").Result()
+		Expect(e).NotTo(HaveOccurred())
+		results, ok := rFail.([]interface{})
+		Expect(ok).To(BeTrue())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0]).To(Equal(int64(128)))
+		Expect(results[1]).To(BeNil())
+		r, e = client.BitField(ctx, DefaultKey, "GET", "u8", "0").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{200}))
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+
+		// A delta far larger than the field's own width must still clamp
+		// correctly instead of overflowing int64 and wrapping back in range.
+		r, e = client.BitField(ctx, DefaultKey, "SET", "i32", "0", "100", "OVERFLOW", "SAT", "INCRBY", "i32", "0", strconv.FormatInt(math.MaxInt64-50, 10)).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{0, math.MaxInt32}))
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
+	It("BitField negative INCRBY and BITFIELD_RO", func() {
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+
+		r, e := client.BitField(ctx, DefaultKey, "SET", "u16", "0", "1000").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r).To(Equal([]int64{0}))
+
+		r, e = client.BitField(ctx, DefaultKey, "INCRBY", "u16", "0", "-2000").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(r[0]).NotTo(Equal(int64(0)))
+
+		roRes, e := client.Do(ctx, "BITFIELD_RO", DefaultKey, "GET", "u16", "0").Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(roRes).To(Equal([]interface{}{r[0]}))
+
+		_, e = client.Do(ctx, "BITFIELD_RO", DefaultKey, "SET", "u16", "0", "0").Result()
+		Expect(e).To(HaveOccurred())
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
+	It("Cmd LCS", func() {
+		Expect(client.MSet(ctx, "lcskey1", "ohmytext", "lcskey2", "mynewtext").Err()).NotTo(HaveOccurred())
+
+		lcs, e := client.LCS(ctx, &redis.LCSQuery{Key1: "lcskey1", Key2: "lcskey2"}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(lcs.MatchString).To(Equal("mytext"))
+
+		lcsLen, e := client.LCS(ctx, &redis.LCSQuery{Key1: "lcskey1", Key2: "lcskey2", Len: true}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(lcsLen.Len).To(Equal(int64(6)))
+
+		lcsIdx, e := client.LCS(ctx, &redis.LCSQuery{Key1: "lcskey1", Key2: "lcskey2", Idx: true}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(lcsIdx.Len).To(Equal(int64(6)))
+		Expect(lcsIdx.Matches).To(Equal([]redis.LCSMatchedPosition{
+			{
+				Key1: redis.LCSPosition{Start: 4, End: 7},
+				Key2: redis.LCSPosition{Start: 5, End: 8},
+			},
+			{
+				Key1: redis.LCSPosition{Start: 2, End: 3},
+				Key2: redis.LCSPosition{Start: 0, End: 1},
+			},
+		}))
+
+		lcsIdxMin, e := client.LCS(ctx, &redis.LCSQuery{
+			Key1: "lcskey1", Key2: "lcskey2", Idx: true, MinMatchLen: 4,
+		}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(lcsIdxMin.Matches).To(Equal([]redis.LCSMatchedPosition{
+			{
+				Key1: redis.LCSPosition{Start: 4, End: 7},
+				Key2: redis.LCSPosition{Start: 5, End: 8},
+			},
+		}))
+
+		lcsIdxLen, e := client.LCS(ctx, &redis.LCSQuery{
+			Key1: "lcskey1", Key2: "lcskey2", Idx: true, WithMatchLen: true,
+		}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(lcsIdxLen.Matches[0].MatchLen).To(Equal(int64(4)))
+		Expect(lcsIdxLen.Matches[1].MatchLen).To(Equal(int64(2)))
+
+		// Missing keys are treated as empty strings.
+		lcsEmpty, e := client.LCS(ctx, &redis.LCSQuery{Key1: "lcskey1", Key2: "nosuchkey"}).Result()
+		Expect(e).NotTo(HaveOccurred())
+		Expect(lcsEmpty.MatchString).To(Equal(""))
+
+		// WRONGTYPE when either key holds a non-string value.
+		Expect(client.Del(ctx, "lcskey3").Err()).NotTo(HaveOccurred())
+		Expect(client.LPush(ctx, "lcskey3", "v1").Err()).NotTo(HaveOccurred())
+		_, e = client.LCS(ctx, &redis.LCSQuery{Key1: "lcskey1", Key2: "lcskey3"}).Result()
+		Expect(e).To(MatchError(ContainSubstring("WRONGTYPE")))
+
+		Expect(client.Del(ctx, "lcskey1", "lcskey2", "lcskey3").Err()).NotTo(HaveOccurred())
+	})
+
 	It("should GetSet", func() {
 		incr := client.Incr(ctx, DefaultKey)
 		Expect(incr.Err()).NotTo(HaveOccurred())
@@ -267,4 +650,82 @@ var _ = Describe("String", Ordered, func() {
 		Expect(mSetnx.Val()).To(Equal(false))
 	})
 
+	It("Keyspace notifications for string mutations", func() {
+		Expect(client.ConfigSet(ctx, "notify-keyspace-events", "AKE").Err()).NotTo(HaveOccurred())
+		defer func() {
+			Expect(client.ConfigSet(ctx, "notify-keyspace-events", "").Err()).NotTo(HaveOccurred())
+		}()
+
+		subCtx, cancel := context.WithCancel(ctx)
+		pubsub := client.PSubscribe(subCtx, "__keyspace@0__:*", "__keyevent@0__:*")
+		defer pubsub.Close()
+		_, e := pubsub.Receive(subCtx)
+		Expect(e).NotTo(HaveOccurred())
+
+		ch := pubsub.Channel()
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+		Expect(client.Set(ctx, DefaultKey, "hello", 0).Err()).NotTo(HaveOccurred())
+
+		var keyspaceMsg, keyeventMsg *redis.Message
+		for keyspaceMsg == nil || keyeventMsg == nil {
+			select {
+			case msg := <-ch:
+				switch msg.Channel {
+				case "__keyspace@0__:" + DefaultKey:
+					keyspaceMsg = msg
+				case "__keyevent@0__:set":
+					keyeventMsg = msg
+				}
+			case <-time.After(3 * time.Second):
+				Fail("timed out waiting for keyspace/keyevent notifications")
+			}
+		}
+		Expect(keyspaceMsg.Payload).To(Equal("set"))
+		Expect(keyeventMsg.Payload).To(Equal(DefaultKey))
+
+		// Cancelling the subscription context drops the subscriber cleanly:
+		// the channel closes rather than leaking or blocking.
+		cancel()
+		Eventually(ch).Should(BeClosed())
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
+	It("Keyspace notification publishing isn't blocked by a stuck subscriber", func() {
+		Expect(client.ConfigSet(ctx, "notify-keyspace-events", "AKE").Err()).NotTo(HaveOccurred())
+		defer func() {
+			Expect(client.ConfigSet(ctx, "notify-keyspace-events", "").Err()).NotTo(HaveOccurred())
+		}()
+
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stuck := client.PSubscribe(subCtx, "__keyspace@0__:*")
+		defer stuck.Close()
+		_, e := stuck.Receive(subCtx)
+		Expect(e).NotTo(HaveOccurred())
+
+		// Deliberately never drain stuck's channel: each SET below publishes
+		// a keyspace notification, so well past the subscriber's 100-slot
+		// buffer these are all dropped for it. The publisher must not block
+		// waiting for room; it just keeps dropping and returns promptly.
+		const mutations = 150
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer GinkgoRecover()
+			for i := 0; i < mutations; i++ {
+				Expect(client.Set(ctx, DefaultKey, strconv.Itoa(i), 0).Err()).NotTo(HaveOccurred())
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			Fail("SET calls blocked behind a full subscriber buffer")
+		}
+
+		Expect(client.Del(ctx, DefaultKey).Err()).NotTo(HaveOccurred())
+	})
+
 })